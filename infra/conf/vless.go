@@ -30,15 +30,17 @@ type VLessInboundFallback struct {
 }
 
 type VLessInboundConfig struct {
-	Clients    []json.RawMessage       `json:"clients"`
-	Decryption string                  `json:"decryption"`
-	Fallbacks  []*VLessInboundFallback `json:"fallbacks"`
-	Flow       string                  `json:"flow"`
+	Clients     []json.RawMessage       `json:"clients"`
+	Decryption  string                  `json:"decryption"`
+	Fallbacks   []*VLessInboundFallback `json:"fallbacks"`
+	Flow        string                  `json:"flow"`
+	AllowZeroID bool                    `json:"allowZeroId"`
 }
 
 // Build implements Buildable
 func (c *VLessInboundConfig) Build() (proto.Message, error) {
 	config := new(inbound.Config)
+	config.AllowZeroId = c.AllowZeroID
 	config.Clients = make([]*protocol.User, len(c.Clients))
 	switch c.Flow {
 	case vless.None:
@@ -155,5 +155,53 @@ func TestVLessInbound(t *testing.T) {
 				},
 			},
 		},
+		{
+			Input: `{
+				"clients": [
+					{
+						"id": "00000000-0000-0000-0000-000000000000",
+						"email": "zero@example.com"
+					}
+				],
+				"decryption": "none"
+			}`,
+			Parser: loadJSON(creator),
+			Output: &inbound.Config{
+				Clients: []*protocol.User{
+					{
+						Account: serial.ToTypedMessage(&vless.Account{
+							Id: "00000000-0000-0000-0000-000000000000",
+						}),
+						Email: "zero@example.com",
+					},
+				},
+				Decryption: "none",
+			},
+		},
+		{
+			Input: `{
+				"clients": [
+					{
+						"id": "00000000-0000-0000-0000-000000000000",
+						"email": "zero@example.com"
+					}
+				],
+				"decryption": "none",
+				"allowZeroId": true
+			}`,
+			Parser: loadJSON(creator),
+			Output: &inbound.Config{
+				Clients: []*protocol.User{
+					{
+						Account: serial.ToTypedMessage(&vless.Account{
+							Id: "00000000-0000-0000-0000-000000000000",
+						}),
+						Email: "zero@example.com",
+					},
+				},
+				Decryption:  "none",
+				AllowZeroId: true,
+			},
+		},
 	})
 }
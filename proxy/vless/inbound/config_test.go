@@ -0,0 +1,36 @@
+package inbound
+
+import (
+	"testing"
+
+	"github.com/xtls/xray-core/common/protocol"
+	"github.com/xtls/xray-core/common/serial"
+	"github.com/xtls/xray-core/common/uuid"
+	"github.com/xtls/xray-core/proxy/vless"
+)
+
+func TestNewValidatorZeroID(t *testing.T) {
+	c := &Config{
+		Clients: []*protocol.User{
+			{
+				Account: serial.ToTypedMessage(&vless.Account{
+					Id: "00000000-0000-0000-0000-000000000000",
+				}),
+				Email: "zero@example.com",
+			},
+		},
+	}
+
+	if _, err := newValidator(c); err == nil {
+		t.Error("expected the all-zero ID to be rejected by default")
+	}
+
+	c.AllowZeroId = true
+	v, err := newValidator(c)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if v.Get(uuid.UUID{}) == nil {
+		t.Error("expected the all-zero ID to be accepted with allowZeroId")
+	}
+}
@@ -54,21 +54,29 @@ func init() {
 
 		c := config.(*Config)
 
-		validator := new(vless.MemoryValidator)
-		for _, user := range c.Clients {
-			u, err := user.ToMemoryUser()
-			if err != nil {
-				return nil, errors.New("failed to get VLESS user").Base(err).AtError()
-			}
-			if err := validator.Add(u); err != nil {
-				return nil, errors.New("failed to initiate user").Base(err).AtError()
-			}
+		validator, err := newValidator(c)
+		if err != nil {
+			return nil, err
 		}
 
 		return New(ctx, c, dc, validator)
 	}))
 }
 
+func newValidator(c *Config) (*vless.MemoryValidator, error) {
+	validator := &vless.MemoryValidator{AllowZeroID: c.AllowZeroId}
+	for _, user := range c.Clients {
+		u, err := user.ToMemoryUser()
+		if err != nil {
+			return nil, errors.New("failed to get VLESS user").Base(err).AtError()
+		}
+		if err := validator.Add(u); err != nil {
+			return nil, errors.New("failed to initiate user").Base(err).AtError()
+		}
+	}
+	return validator, nil
+}
+
 // Handler is an inbound connection handler that handles messages in VLess protocol.
 type Handler struct {
 	inboundHandlerManager  feature_inbound.Manager
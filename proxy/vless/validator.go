@@ -18,6 +18,23 @@ type Validator interface {
 	GetCount() int64
 }
 
+var (
+	// ErrNotVLESSUser is returned when a user without a VLESS account is given to the validator.
+	ErrNotVLESSUser = errors.New("Not a VLESS user.")
+	// ErrDuplicateEmail is returned by Add when the email is already used by another user.
+	ErrDuplicateEmail = errors.New("Duplicate email.")
+	// ErrDuplicateID is returned by Add when the ID is already used by another user.
+	ErrDuplicateID = errors.New("Duplicate ID.")
+)
+
+func asVLESSAccount(u *protocol.MemoryUser) (*MemoryAccount, error) {
+	a, ok := u.Account.(*MemoryAccount)
+	if !ok {
+		return nil, ErrNotVLESSUser
+	}
+	return a, nil
+}
+
 func ProcessUUID(id [16]byte) [16]byte {
 	id[6] = 0
 	id[7] = 0
@@ -29,17 +46,35 @@ type MemoryValidator struct {
 	// Considering email's usage here, map + sync.Mutex/RWMutex may have better performance.
 	email sync.Map
 	users sync.Map
+
+	// AllowZeroID disables the rejection of the all-zero UUID, which is
+	// almost always a misconfigured client rather than a real user.
+	AllowZeroID bool
 }
 
 // Add a VLESS user, Email must be empty or unique.
 func (v *MemoryValidator) Add(u *protocol.MemoryUser) error {
+	a, err := asVLESSAccount(u)
+	if err != nil {
+		return errors.New("User ", u.Email, " cannot be added.").Base(err)
+	}
+	id := ProcessUUID(a.ID.UUID())
+	if !v.AllowZeroID && id == [16]byte{} {
+		return errors.New("User ", u.Email, ` must not use the all-zero ID, set "allowZeroId" in the VLESS inbound settings to allow it.`)
+	}
+	le := strings.ToLower(u.Email)
 	if u.Email != "" {
-		_, loaded := v.email.LoadOrStore(strings.ToLower(u.Email), u)
+		_, loaded := v.email.LoadOrStore(le, u)
 		if loaded {
-			return errors.New("User ", u.Email, " already exists.")
+			return errors.New("User ", u.Email, " already exists.").Base(ErrDuplicateEmail)
 		}
 	}
-	v.users.Store(ProcessUUID(u.Account.(*MemoryAccount).ID.UUID()), u)
+	if _, loaded := v.users.LoadOrStore(id, u); loaded {
+		if u.Email != "" {
+			v.email.CompareAndDelete(le, u)
+		}
+		return errors.New("User ", u.Email, " uses the same ID as an existing user.").Base(ErrDuplicateID)
+	}
 	return nil
 }
 
@@ -54,13 +89,17 @@ func (v *MemoryValidator) Del(e string) error {
 		return errors.New("User ", e, " not found.")
 	}
 	v.email.Delete(le)
-	v.users.Delete(ProcessUUID(u.(*protocol.MemoryUser).Account.(*MemoryAccount).ID.UUID()))
+	v.users.CompareAndDelete(ProcessUUID(u.(*protocol.MemoryUser).Account.(*MemoryAccount).ID.UUID()), u)
 	return nil
 }
 
 // Get a VLESS user with UUID, nil if user doesn't exist.
 func (v *MemoryValidator) Get(id uuid.UUID) *protocol.MemoryUser {
-	u, _ := v.users.Load(ProcessUUID(id))
+	pid := ProcessUUID(id)
+	if !v.AllowZeroID && pid == [16]byte{} {
+		return nil
+	}
+	u, _ := v.users.Load(pid)
 	if u != nil {
 		return u.(*protocol.MemoryUser)
 	}
@@ -0,0 +1,210 @@
+package vless_test
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+	"testing"
+
+	"google.golang.org/protobuf/proto"
+
+	"github.com/xtls/xray-core/common"
+	"github.com/xtls/xray-core/common/protocol"
+	"github.com/xtls/xray-core/common/uuid"
+	. "github.com/xtls/xray-core/proxy/vless"
+)
+
+type otherAccount struct{}
+
+func (*otherAccount) Equals(protocol.Account) bool { return false }
+
+func (*otherAccount) ToProto() proto.Message { return nil }
+
+// The benchmarks below are a baseline for the handshake lookup path.
+// Run them with
+//
+//	go test -run ^$ -bench MemoryValidator -benchmem ./proxy/vless/
+//
+// and compare runs with benchstat. Get and GetByEmail are expected to
+// stay at 0 allocs/op; the Parallel variant shows contention.
+
+func newBenchValidator(b *testing.B, n int) (*MemoryValidator, []uuid.UUID, []string) {
+	b.Helper()
+	v := new(MemoryValidator)
+	ids := make([]uuid.UUID, n)
+	emails := make([]string, n)
+	for i := range ids {
+		ids[i] = uuid.New()
+		emails[i] = "user" + strconv.Itoa(i)
+		common.Must(v.Add(&protocol.MemoryUser{
+			Email: emails[i],
+			Account: &MemoryAccount{
+				ID: protocol.NewID(ids[i]),
+			},
+		}))
+	}
+	return v, ids, emails
+}
+
+func BenchmarkMemoryValidatorGet(b *testing.B) {
+	v, ids, _ := newBenchValidator(b, 1000)
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if v.Get(ids[i%len(ids)]) == nil {
+			b.Fatal("user not found")
+		}
+	}
+}
+
+func BenchmarkMemoryValidatorGetMiss(b *testing.B) {
+	v, _, _ := newBenchValidator(b, 1000)
+	id := uuid.New()
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if v.Get(id) != nil {
+			b.Fatal("unexpected user")
+		}
+	}
+}
+
+func BenchmarkMemoryValidatorGetByEmail(b *testing.B) {
+	v, _, emails := newBenchValidator(b, 1000)
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if v.GetByEmail(emails[i%len(emails)]) == nil {
+			b.Fatal("user not found")
+		}
+	}
+}
+
+func BenchmarkMemoryValidatorGetParallel(b *testing.B) {
+	v, ids, _ := newBenchValidator(b, 1000)
+	b.ReportAllocs()
+	b.ResetTimer()
+	b.RunParallel(func(pb *testing.PB) {
+		i := 0
+		for pb.Next() {
+			if v.Get(ids[i%len(ids)]) == nil {
+				b.Error("user not found")
+				return
+			}
+			i++
+		}
+	})
+}
+
+func BenchmarkMemoryValidatorAddDel(b *testing.B) {
+	v, _, _ := newBenchValidator(b, 1000)
+	u := &protocol.MemoryUser{
+		Email: "churn",
+		Account: &MemoryAccount{
+			ID: protocol.NewID(uuid.New()),
+		},
+	}
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		common.Must(v.Add(u))
+		common.Must(v.Del(u.Email))
+	}
+}
+
+func TestMemoryValidatorZeroID(t *testing.T) {
+	zero := &protocol.MemoryUser{
+		Email: "zero",
+		Account: &MemoryAccount{
+			ID: protocol.NewID(uuid.UUID{}),
+		},
+	}
+
+	v := new(MemoryValidator)
+	if err := v.Add(zero); err == nil || !strings.Contains(err.Error(), "allowZeroId") {
+		t.Error("expected error naming allowZeroId when adding the all-zero ID, but got ", err)
+	}
+	if v.GetByEmail("zero") != nil {
+		t.Error("rejected user must not be indexed by email")
+	}
+	if v.Get(uuid.UUID{}) != nil {
+		t.Error("expected nil for the all-zero ID")
+	}
+
+	v = &MemoryValidator{AllowZeroID: true}
+	common.Must(v.Add(zero))
+	if v.Get(uuid.UUID{}) != zero {
+		t.Error("expected the all-zero ID to be accepted when allowed")
+	}
+}
+
+func TestMemoryValidatorNotVLESSUser(t *testing.T) {
+	v := new(MemoryValidator)
+	for _, u := range []*protocol.MemoryUser{
+		{Email: "other", Account: &otherAccount{}},
+		{Email: "other"},
+	} {
+		if err := v.Add(u); !errors.Is(err, ErrNotVLESSUser) {
+			t.Error("expected ErrNotVLESSUser, but got ", err)
+		}
+	}
+	if v.GetByEmail("other") != nil || v.GetCount() != 0 {
+		t.Error("rejected user must not be stored")
+	}
+}
+
+func TestMemoryValidatorDuplicateID(t *testing.T) {
+	id := protocol.NewID(uuid.New())
+	a := &protocol.MemoryUser{Email: "a", Account: &MemoryAccount{ID: id}}
+	b := &protocol.MemoryUser{Email: "b", Account: &MemoryAccount{ID: id}}
+	c := &protocol.MemoryUser{Email: "c", Account: &MemoryAccount{ID: protocol.NewID(uuid.New())}}
+
+	v := new(MemoryValidator)
+	common.Must(v.Add(a))
+	common.Must(v.Add(c))
+	if err := v.Add(b); !errors.Is(err, ErrDuplicateID) {
+		t.Error("expected ErrDuplicateID, but got ", err)
+	}
+	if v.GetByEmail("b") != nil || v.GetCount() != 2 {
+		t.Error("rejected user must not be indexed by email")
+	}
+	if v.Get(id.UUID()) != a {
+		t.Error("existing user must keep its ID")
+	}
+
+	common.Must(v.Del("a"))
+	if v.Get(id.UUID()) != nil {
+		t.Error("deleted user must not be found by ID")
+	}
+	if v.Get(c.Account.(*MemoryAccount).ID.UUID()) != c || v.GetByEmail("c") != c {
+		t.Error("deleting a user must not affect other users")
+	}
+
+	common.Must(v.Add(b))
+	if v.Get(id.UUID()) != b {
+		t.Error("ID must be reusable after the previous user is deleted")
+	}
+}
+
+func TestMemoryValidatorDuplicateEmail(t *testing.T) {
+	v := new(MemoryValidator)
+	common.Must(v.Add(&protocol.MemoryUser{
+		Email:   "a@example.com",
+		Account: &MemoryAccount{ID: protocol.NewID(uuid.New())},
+	}))
+
+	id := uuid.New()
+	err := v.Add(&protocol.MemoryUser{
+		Email:   "A@example.com",
+		Account: &MemoryAccount{ID: protocol.NewID(id)},
+	})
+	if !errors.Is(err, ErrDuplicateEmail) {
+		t.Error("expected ErrDuplicateEmail, but got ", err)
+	}
+	if errors.Is(err, ErrDuplicateID) {
+		t.Error("a duplicate email must not be reported as a duplicate ID")
+	}
+	if v.Get(id) != nil {
+		t.Error("rejected user must not be indexed by ID")
+	}
+}